@@ -1,6 +1,7 @@
 package actors
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -15,9 +16,9 @@ type CacheDumper struct {
 }
 
 // Do performst the actor task.
-func (c *CacheDumper) Do() error {
+func (c *CacheDumper) Do(ctx context.Context) error {
 	// Get all entries from the repo.
-	m, err := c.Repo.GetAll()
+	m, err := c.Repo.GetAll(ctx)
 	if err != nil {
 		log.Printf("error reading the local cache: %s\n", err.Error())
 		return err
@@ -0,0 +1,147 @@
+package actors
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+// PayloadMode controls how a single Measurement is turned into MQTT messages.
+type PayloadMode int
+
+const (
+	// PayloadJSON publishes the whole Measurement as one JSON message under
+	// <base>/<meterid>.
+	PayloadJSON PayloadMode = iota
+	// PayloadPerOBIS publishes one message per OBIS code, under
+	// <base>/<meterid>/<obis>.
+	PayloadPerOBIS
+)
+
+// MQTTPublisherOptions configures an MQTTPublisher.
+type MQTTPublisherOptions struct {
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+
+	BaseTopic string
+	MeterID   string
+	Mode      PayloadMode
+
+	QoS      byte
+	Retained bool
+
+	TLSConfig *tls.Config
+
+	// LastWillTopic and LastWillPayload, when LastWillTopic is non-empty,
+	// register a last-will message with the broker.
+	LastWillTopic   string
+	LastWillPayload string
+	LastWillQoS     byte
+	LastWillRetain  bool
+}
+
+// MQTTPublisher type reads measurements off a channel and publishes them to
+// an MQTT broker/topic. It is a peer of CacheDumper: where CacheDumper drains
+// the repo once, MQTTPublisher runs for the lifetime of the polling loop,
+// publishing every measurement as it arrives.
+type MQTTPublisher struct {
+	Options MQTTPublisherOptions
+	In      <-chan *model.Measurement
+
+	client mqtt.Client
+}
+
+// buildClientOptions turns opts into the mqtt.ClientOptions NewMQTTPublisher
+// connects with, split out so the option wiring can be tested without
+// dialing a broker.
+func buildClientOptions(opts MQTTPublisherOptions) *mqtt.ClientOptions {
+	co := mqtt.NewClientOptions().AddBroker(opts.Broker).SetClientID(opts.ClientID)
+	if opts.Username != "" {
+		co.SetUsername(opts.Username)
+		co.SetPassword(opts.Password)
+	}
+	if opts.TLSConfig != nil {
+		co.SetTLSConfig(opts.TLSConfig)
+	}
+	if opts.LastWillTopic != "" {
+		co.SetWill(opts.LastWillTopic, opts.LastWillPayload, opts.LastWillQoS, opts.LastWillRetain)
+	}
+	return co
+}
+
+// NewMQTTPublisher dials the broker described by opts and returns a publisher
+// ready to have Do called on it.
+func NewMQTTPublisher(opts MQTTPublisherOptions, in <-chan *model.Measurement) (*MQTTPublisher, error) {
+	c := mqtt.NewClient(buildClientOptions(opts))
+	if t := c.Connect(); t.Wait() && t.Error() != nil {
+		return nil, fmt.Errorf("mqtt: connecting to %s: %w", opts.Broker, t.Error())
+	}
+
+	return &MQTTPublisher{
+		Options: opts,
+		In:      in,
+		client:  c,
+	}, nil
+}
+
+// Do performs the actor task: it drains In until the channel is closed,
+// publishing every measurement as it arrives. It returns the first publish
+// error encountered, having already logged it.
+func (p *MQTTPublisher) Do() error {
+	for msm := range p.In {
+		if err := p.publish(msm); err != nil {
+			log.Printf("error publishing measurement to mqtt: %s\n", err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *MQTTPublisher) publish(msm *model.Measurement) error {
+	switch p.Options.Mode {
+	case PayloadPerOBIS:
+		return p.publishPerOBIS(msm)
+	default:
+		return p.publishJSON(msm)
+	}
+}
+
+func (p *MQTTPublisher) publishJSON(msm *model.Measurement) error {
+	b, err := json.Marshal(msm)
+	if err != nil {
+		return fmt.Errorf("mqtt: marshalling measurement: %w", err)
+	}
+	topic := fmt.Sprintf("%s/%s", p.Options.BaseTopic, p.Options.MeterID)
+	t := p.client.Publish(topic, p.Options.QoS, p.Options.Retained, b)
+	if t.Wait() && t.Error() != nil {
+		return fmt.Errorf("mqtt: publishing to %s: %w", topic, t.Error())
+	}
+	return nil
+}
+
+func (p *MQTTPublisher) publishPerOBIS(msm *model.Measurement) error {
+	for _, reg := range msm.Registers {
+		b, err := json.Marshal(reg)
+		if err != nil {
+			return fmt.Errorf("mqtt: marshalling register %s: %w", reg.OBIS, err)
+		}
+		topic := fmt.Sprintf("%s/%s/%s", p.Options.BaseTopic, p.Options.MeterID, reg.OBIS)
+		t := p.client.Publish(topic, p.Options.QoS, p.Options.Retained, b)
+		if t.Wait() && t.Error() != nil {
+			return fmt.Errorf("mqtt: publishing to %s: %w", topic, t.Error())
+		}
+	}
+	return nil
+}
+
+// Close disconnects the underlying MQTT client.
+func (p *MQTTPublisher) Close() {
+	p.client.Disconnect(250)
+}
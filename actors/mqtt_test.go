@@ -0,0 +1,210 @@
+package actors
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+// fakeToken is a completed mqtt.Token with a fixed error, so tests don't
+// depend on a real broker round trip.
+type fakeToken struct{ err error }
+
+func (t *fakeToken) Wait() bool                     { return true }
+func (t *fakeToken) WaitTimeout(time.Duration) bool { return true }
+func (t *fakeToken) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+func (t *fakeToken) Error() error { return t.err }
+
+// fakePublish records one Publish call.
+type fakePublish struct {
+	topic    string
+	qos      byte
+	retained bool
+	payload  interface{}
+}
+
+// fakeClient implements mqtt.Client, recording every Publish call and
+// returning publishErr (if set) via the returned Token.
+type fakeClient struct {
+	published  []fakePublish
+	publishErr error
+}
+
+func (c *fakeClient) IsConnected() bool      { return true }
+func (c *fakeClient) IsConnectionOpen() bool { return true }
+func (c *fakeClient) Connect() mqtt.Token    { return &fakeToken{} }
+func (c *fakeClient) Disconnect(quiesce uint) {}
+func (c *fakeClient) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	c.published = append(c.published, fakePublish{topic: topic, qos: qos, retained: retained, payload: payload})
+	return &fakeToken{err: c.publishErr}
+}
+func (c *fakeClient) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	return &fakeToken{}
+}
+func (c *fakeClient) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	return &fakeToken{}
+}
+func (c *fakeClient) Unsubscribe(topics ...string) mqtt.Token { return &fakeToken{} }
+func (c *fakeClient) AddRoute(topic string, callback mqtt.MessageHandler) {}
+func (c *fakeClient) OptionsReader() mqtt.ClientOptionsReader {
+	return mqtt.NewOptionsReader(mqtt.NewClientOptions())
+}
+
+func TestMQTTPublisherPublishJSON(t *testing.T) {
+	fc := &fakeClient{}
+	p := &MQTTPublisher{
+		Options: MQTTPublisherOptions{
+			BaseTopic: "meters",
+			MeterID:   "meter1",
+			QoS:       1,
+			Retained:  true,
+		},
+		client: fc,
+	}
+
+	msm := &model.Measurement{
+		Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Registers: []model.Register{{OBIS: "1.8.0", Value: 123.4, Unit: "kWh"}},
+	}
+	if err := p.publishJSON(msm); err != nil {
+		t.Fatalf("publishJSON: %s", err.Error())
+	}
+
+	if len(fc.published) != 1 {
+		t.Fatalf("got %d Publish calls, want 1", len(fc.published))
+	}
+	got := fc.published[0]
+	if got.topic != "meters/meter1" {
+		t.Errorf("topic = %q, want %q", got.topic, "meters/meter1")
+	}
+	if got.qos != 1 || !got.retained {
+		t.Errorf("qos/retained = %d/%v, want 1/true", got.qos, got.retained)
+	}
+
+	var decoded model.Measurement
+	if err := json.Unmarshal(got.payload.([]byte), &decoded); err != nil {
+		t.Fatalf("unmarshalling published payload: %s", err.Error())
+	}
+	if !decoded.Timestamp.Equal(msm.Timestamp) {
+		t.Errorf("published timestamp = %s, want %s", decoded.Timestamp, msm.Timestamp)
+	}
+}
+
+func TestMQTTPublisherPublishPerOBIS(t *testing.T) {
+	fc := &fakeClient{}
+	p := &MQTTPublisher{
+		Options: MQTTPublisherOptions{
+			BaseTopic: "meters",
+			MeterID:   "meter1",
+			Mode:      PayloadPerOBIS,
+		},
+		client: fc,
+	}
+
+	msm := &model.Measurement{
+		Registers: []model.Register{
+			{OBIS: "1.8.0", Value: 1, Unit: "kWh"},
+			{OBIS: "2.8.0", Value: 2, Unit: "kWh"},
+		},
+	}
+	if err := p.publish(msm); err != nil {
+		t.Fatalf("publish: %s", err.Error())
+	}
+
+	if len(fc.published) != 2 {
+		t.Fatalf("got %d Publish calls, want 2", len(fc.published))
+	}
+	wantTopics := []string{"meters/meter1/1.8.0", "meters/meter1/2.8.0"}
+	for i, want := range wantTopics {
+		if fc.published[i].topic != want {
+			t.Errorf("published[%d].topic = %q, want %q", i, fc.published[i].topic, want)
+		}
+	}
+}
+
+func TestMQTTPublisherPublishError(t *testing.T) {
+	fc := &fakeClient{publishErr: errors.New("mqtt: broken pipe")}
+	p := &MQTTPublisher{
+		Options: MQTTPublisherOptions{BaseTopic: "meters", MeterID: "meter1"},
+		client:  fc,
+	}
+
+	if err := p.publishJSON(&model.Measurement{}); err == nil {
+		t.Fatal("expected publishJSON to surface the token's error")
+	}
+}
+
+func TestMQTTPublisherDoDrainsUntilClosed(t *testing.T) {
+	fc := &fakeClient{}
+	in := make(chan *model.Measurement, 2)
+	in <- &model.Measurement{Registers: []model.Register{{OBIS: "1.8.0", Value: 1}}}
+	in <- &model.Measurement{Registers: []model.Register{{OBIS: "1.8.0", Value: 2}}}
+	close(in)
+
+	p := &MQTTPublisher{Options: MQTTPublisherOptions{BaseTopic: "meters", MeterID: "meter1"}, In: in, client: fc}
+	if err := p.Do(); err != nil {
+		t.Fatalf("Do: %s", err.Error())
+	}
+	if len(fc.published) != 2 {
+		t.Fatalf("got %d Publish calls, want 2", len(fc.published))
+	}
+}
+
+func TestBuildClientOptionsWiresLastWillAndTLS(t *testing.T) {
+	tlsConfig := &tls.Config{}
+	co := buildClientOptions(MQTTPublisherOptions{
+		Broker:          "tcp://localhost:1883",
+		ClientID:        "iec62056",
+		TLSConfig:       tlsConfig,
+		LastWillTopic:   "meters/meter1/status",
+		LastWillPayload: "offline",
+		LastWillQoS:     1,
+		LastWillRetain:  true,
+	})
+
+	r := mqtt.NewOptionsReader(co)
+	if r.ClientID() != "iec62056" {
+		t.Errorf("ClientID = %q, want %q", r.ClientID(), "iec62056")
+	}
+	if r.TLSConfig() != tlsConfig {
+		t.Error("TLSConfig was not wired onto the client options")
+	}
+	if !r.WillEnabled() {
+		t.Fatal("expected the last will to be enabled")
+	}
+	if r.WillTopic() != "meters/meter1/status" {
+		t.Errorf("WillTopic = %q, want %q", r.WillTopic(), "meters/meter1/status")
+	}
+	if string(r.WillPayload()) != "offline" {
+		t.Errorf("WillPayload = %q, want %q", r.WillPayload(), "offline")
+	}
+	if r.WillQos() != 1 || !r.WillRetained() {
+		t.Errorf("WillQos/WillRetained = %d/%v, want 1/true", r.WillQos(), r.WillRetained())
+	}
+}
+
+func TestBuildClientOptionsWiresUsernamePassword(t *testing.T) {
+	co := buildClientOptions(MQTTPublisherOptions{
+		Broker:   "tcp://localhost:1883",
+		Username: "alice",
+		Password: "secret",
+	})
+
+	r := mqtt.NewOptionsReader(co)
+	if r.Username() != "alice" {
+		t.Errorf("Username = %q, want %q", r.Username(), "alice")
+	}
+	if r.Password() != "secret" {
+		t.Errorf("Password = %q, want %q", r.Password(), "secret")
+	}
+}
@@ -0,0 +1,26 @@
+package actors
+
+import (
+	"context"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+// PagerActor reads single or cursor-paginated entries from the Repo for the
+// HTTP measurements API. ctx is passed straight through to the Repo, so
+// binding it to a request's lifetime (client disconnect) or a service's
+// shutdown lets a caller abort an in-flight read instead of blocking on it.
+type PagerActor struct {
+	Repo model.MeasurementRepo
+}
+
+// Get returns the Measurement stored under key (model.First or model.Last).
+func (a *PagerActor) Get(ctx context.Context, key string) (*model.Measurement, error) {
+	return a.Repo.Get(ctx, key)
+}
+
+// Query returns a cursor-paginated, optionally filtered page of
+// Measurements. See model.MeasurementRepo.Query for the parameters.
+func (a *PagerActor) Query(ctx context.Context, filter model.Filter, cursor string, size int) (*model.QueryPage, error) {
+	return a.Repo.Query(ctx, filter, cursor, size)
+}
@@ -0,0 +1,83 @@
+// Package meter adapts the iec protocol package to a single named serial
+// port, producing model.Measurement values.
+package meter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tarm/serial"
+
+	"github.com/mariuszjedrzejewski/iec62056/iec"
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+// Meter reads measurements off a single serial port using the IEC 62056-21
+// protocol.
+type Meter struct {
+	PortSettings iec.PortSettings
+	PortName     string
+}
+
+// Get runs a single IEC 62056-21 read dialog and returns the resulting
+// Measurement. ctx bounds the whole dialog: if it is cancelled or its
+// deadline expires before the meter responds, Get returns an error wrapping
+// ctx.Err() instead of hanging. A nil ctx is treated as context.Background().
+//
+// The dialog shape depends on PortSettings.Mode: ModeD skips the
+// identification exchange entirely and just reads the meter's unsolicited
+// push; ModeAuto and ModeC negotiate via "/?!" and then reopen the port at
+// the meter's advertised baud rate; ModeA and ModeB negotiate but keep the
+// configured baud rate throughout.
+func (m *Meter) Get(ctx context.Context) (*model.Measurement, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	settings := m.PortSettings
+	port, err := openPort(m.PortName, settings)
+	if err != nil {
+		return nil, fmt.Errorf("meter: opening port %s: %w", m.PortName, err)
+	}
+	defer func() { port.Close() }()
+
+	sess := iec.NewSession(port, settings)
+
+	if settings.Mode != iec.ModeD {
+		id, err := sess.RequestIdentification(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("meter: requesting identification: %w", err)
+		}
+		if err := sess.AcknowledgeMode(ctx, id.BaudChar); err != nil {
+			return nil, fmt.Errorf("meter: acknowledging mode: %w", err)
+		}
+
+		if settings.Mode == iec.ModeC || settings.Mode == iec.ModeAuto {
+			port.Close()
+			settings.BaudRate = id.BaudRate
+			if port, err = openPort(m.PortName, settings); err != nil {
+				return nil, fmt.Errorf("meter: reopening port at negotiated baud %d: %w", id.BaudRate, err)
+			}
+			sess = iec.NewSession(port, settings)
+		}
+	}
+
+	data, err := sess.ReadDataBlock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("meter: reading data block: %w", err)
+	}
+	if err := iec.ValidateBCC(data); err != nil {
+		return nil, fmt.Errorf("meter: validating data block: %w", err)
+	}
+
+	return iec.ParseDataBlock(data)
+}
+
+func openPort(name string, settings iec.PortSettings) (*serial.Port, error) {
+	return serial.OpenPort(&serial.Config{
+		Name:     name,
+		Baud:     settings.BaudRate,
+		Size:     byte(settings.DataBits),
+		StopBits: serial.StopBits(settings.StopBits),
+	})
+}
@@ -0,0 +1,26 @@
+package iec
+
+import "fmt"
+
+// ValidateBCC checks the trailing block check character of an IEC 62056-21
+// data block as read by Session.ReadDataBlock: the BCC is the XOR of every
+// byte *after* STX up to and including ETX (STX itself is excluded), and is
+// transmitted as the single byte right after ETX.
+func ValidateBCC(data []byte) error {
+	if len(data) < 3 {
+		return fmt.Errorf("iec: data block too short to contain a bcc")
+	}
+	if data[0] != stx {
+		return fmt.Errorf("iec: data block does not start with stx")
+	}
+
+	payload, bcc := data[1:len(data)-1], data[len(data)-1]
+	var sum byte
+	for _, b := range payload {
+		sum ^= b
+	}
+	if sum != bcc {
+		return fmt.Errorf("iec: bcc mismatch: got %#02x, computed %#02x", bcc, sum)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package iec
+
+import "testing"
+
+// A typical IEC 62056-21 mode C data block: STX, one line per OBIS
+// register, a "!" end-of-data marker, ETX, then the BCC.
+const testTelegramBody = "1-0:1.8.0(000226.5*kWh)\r\n" +
+	"1-0:1.8.1(000000.0*kWh)\r\n" +
+	"1-0:1.8.2(000226.5*kWh)\r\n" +
+	"!\r\n"
+
+// bcc computes the IEC 62056-21 block check character: the XOR of every
+// byte after STX through ETX inclusive.
+func bcc(body string) byte {
+	var sum byte
+	for i := 0; i < len(body); i++ {
+		sum ^= body[i]
+	}
+	sum ^= etx
+	return sum
+}
+
+func testTelegram() []byte {
+	body := testTelegramBody
+	data := make([]byte, 0, len(body)+2)
+	data = append(data, stx)
+	data = append(data, body...)
+	data = append(data, etx)
+	data = append(data, bcc(body))
+	return data
+}
+
+func TestValidateBCC(t *testing.T) {
+	data := testTelegram()
+	if err := ValidateBCC(data); err != nil {
+		t.Fatalf("ValidateBCC on a well-formed telegram: %s", err.Error())
+	}
+}
+
+func TestValidateBCCMismatch(t *testing.T) {
+	data := testTelegram()
+	data[len(data)-1] ^= 0xFF // corrupt the BCC
+	if err := ValidateBCC(data); err == nil {
+		t.Fatal("ValidateBCC should reject a corrupted bcc")
+	}
+}
+
+func TestValidateBCCTamperedBody(t *testing.T) {
+	data := testTelegram()
+	data[5] ^= 0x01 // corrupt a body byte, leaving the original bcc in place
+	if err := ValidateBCC(data); err == nil {
+		t.Fatal("ValidateBCC should reject a telegram whose body was tampered with")
+	}
+}
+
+func TestValidateBCCTooShort(t *testing.T) {
+	if err := ValidateBCC([]byte{stx, etx}); err == nil {
+		t.Fatal("ValidateBCC should reject a block with no room for a bcc")
+	}
+}
+
+func TestValidateBCCMissingSTX(t *testing.T) {
+	data := testTelegram()
+	data[0] = 'x'
+	if err := ValidateBCC(data); err == nil {
+		t.Fatal("ValidateBCC should reject a block that doesn't start with stx")
+	}
+}
@@ -0,0 +1,36 @@
+package iec
+
+// Mode identifies which IEC 62056-21 transmission mode a session uses.
+type Mode int
+
+const (
+	// ModeAuto negotiates the mode via the standard "/?!" identification
+	// exchange and proceeds at whatever baud rate the meter reports.
+	ModeAuto Mode = iota
+	// ModeA is a fixed 300-baud session with no baud-rate switch.
+	ModeA
+	// ModeB is a fixed session at a baud rate set at manufacture, with no
+	// baud-rate switch.
+	ModeB
+	// ModeC negotiates the mode via the "/?!" exchange and then switches
+	// to the meter's advertised baud rate before reading the data block.
+	ModeC
+	// ModeD is one-way: the meter pushes its data block unsolicited at a
+	// fixed baud rate, with no identification exchange or acknowledgement.
+	ModeD
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeA:
+		return "A"
+	case ModeB:
+		return "B"
+	case ModeC:
+		return "C"
+	case ModeD:
+		return "D"
+	default:
+		return "Auto"
+	}
+}
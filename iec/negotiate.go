@@ -0,0 +1,73 @@
+package iec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// baudCodes maps the IEC 62056-21 baud-rate identification character (the
+// 5th byte of the identification response) to a baud rate.
+var baudCodes = map[byte]int{
+	'0': 300,
+	'1': 600,
+	'2': 1200,
+	'3': 2400,
+	'4': 4800,
+	'5': 9600,
+	'6': 19200,
+}
+
+// Identification is the parsed "/XXXZ..." response to a mode request.
+type Identification struct {
+	Manufacturer string
+	BaudChar     byte
+	BaudRate     int
+	Identity     string
+}
+
+// RequestIdentification sends the "/?!\r\n" mode request and parses the
+// meter's "/XXXZ..." identification response.
+func (s *Session) RequestIdentification(ctx context.Context) (*Identification, error) {
+	if err := s.WriteRequest(ctx, []byte("/?!\r\n")); err != nil {
+		return nil, fmt.Errorf("iec: sending identification request: %w", err)
+	}
+
+	line, err := s.readLine(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("iec: reading identification response: %w", err)
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if !strings.HasPrefix(line, "/") || len(line) < 5 {
+		return nil, fmt.Errorf("iec: malformed identification response %q", line)
+	}
+
+	id := &Identification{
+		Manufacturer: line[1:4],
+		BaudChar:     line[4],
+		Identity:     line[5:],
+	}
+	baud, ok := baudCodes[id.BaudChar]
+	if !ok {
+		return nil, fmt.Errorf("iec: unknown baud-rate identification character %q", id.BaudChar)
+	}
+	id.BaudRate = baud
+	return id, nil
+}
+
+// AcknowledgeMode sends "<ACK>0Z0<CR><LF>", confirming protocol control
+// character "0" (normal, non-programming readout) at the baud rate
+// identified by baudChar.
+func (s *Session) AcknowledgeMode(ctx context.Context, baudChar byte) error {
+	if err := s.WriteRequest(ctx, []byte{ack, '0', baudChar, '0', '\r', '\n'}); err != nil {
+		return fmt.Errorf("iec: sending mode acknowledgement: %w", err)
+	}
+	return nil
+}
+
+// NegotiateHDLC is a stub for IEC 62056-21 Mode E, which hands the session
+// over to the HDLC-framed DLMS/COSEM transport defined by IEC 62056-46.
+// That transport is not implemented by this package.
+func (s *Session) NegotiateHDLC(ctx context.Context) error {
+	return fmt.Errorf("iec: mode E (HDLC handover to 62056-46) is not implemented")
+}
@@ -0,0 +1,59 @@
+package iec
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+// ParseDataBlock parses an IEC 62056-21 data block (one OBIS-code/value line
+// per reading, framed by STX/ETX) into a Measurement. data is expected in
+// the form produced by Session.ReadDataBlock, i.e. ending in ETX followed by
+// a BCC byte; callers should validate that BCC with ValidateBCC first, since
+// ParseDataBlock discards it without checking.
+func ParseDataBlock(data []byte) (*model.Measurement, error) {
+	if len(data) > 0 {
+		data = data[:len(data)-1]
+	}
+	msm := &model.Measurement{Timestamp: time.Now()}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		line = strings.TrimPrefix(line, string(rune(stx)))
+		if line == "" || line[0] == etx {
+			continue
+		}
+
+		open := strings.IndexByte(line, '(')
+		shut := strings.IndexByte(line, ')')
+		if open < 0 || shut < 0 || shut < open {
+			continue
+		}
+		obis := line[:open]
+		val := line[open+1 : shut]
+
+		reg := model.Register{OBIS: obis}
+		if star := strings.IndexByte(val, '*'); star >= 0 {
+			reg.Unit = val[star+1:]
+			val = val[:star]
+		}
+		v, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("iec: parsing value for %s: %w", obis, err)
+		}
+		reg.Value = v
+
+		msm.Registers = append(msm.Registers, reg)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("iec: scanning data block: %w", err)
+	}
+
+	return msm, nil
+}
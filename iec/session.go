@@ -0,0 +1,138 @@
+package iec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	stx = 0x02
+	etx = 0x03
+	ack = 0x06
+)
+
+// Session wraps an open serial port and applies per-operation deadlines to
+// reads and writes, using a timer-and-cancel-channel pattern: the timer is
+// reset on every successful byte and, on expiry, closes a cancel channel
+// that the reading goroutine selects on.
+type Session struct {
+	Port     io.ReadWriter
+	Settings PortSettings
+}
+
+// NewSession wraps an already-opened serial port.
+func NewSession(port io.ReadWriter, settings PortSettings) *Session {
+	return &Session{Port: port, Settings: settings}
+}
+
+type readResult struct {
+	b   byte
+	err error
+}
+
+// readUntil reads single bytes off the port, appending each to the returned
+// slice, until stop reports true for the most recently read byte. It
+// honours ctx and the configured ReadTimeout the same way for every byte.
+func (s *Session) readUntil(ctx context.Context, stop func(b byte) bool) ([]byte, error) {
+	cancel := make(chan struct{})
+	defer close(cancel)
+	bytes := make(chan readResult)
+
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			_, err := s.Port.Read(buf)
+			select {
+			case bytes <- readResult{buf[0], err}:
+			case <-cancel:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var data []byte
+	timer := time.NewTimer(s.Settings.ReadTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, context.DeadlineExceeded
+		case r := <-bytes:
+			if r.err != nil {
+				return nil, fmt.Errorf("iec: reading: %w", r.err)
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(s.Settings.ReadTimeout)
+
+			data = append(data, r.b)
+			if stop(r.b) {
+				return data, nil
+			}
+		}
+	}
+}
+
+// ReadDataBlock reads a single IEC 62056-21 data block: everything up to
+// and including ETX, plus the trailing BCC byte. Validate the result with
+// ValidateBCC before parsing it.
+func (s *Session) ReadDataBlock(ctx context.Context) ([]byte, error) {
+	sawETX := false
+	data, err := s.readUntil(ctx, func(b byte) bool {
+		if sawETX {
+			return true
+		}
+		if b == etx {
+			sawETX = true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, fmt.Errorf("iec: reading data block: %w", err)
+	}
+	return data, nil
+}
+
+// readLine reads up to and including the next '\n', used for line-oriented
+// exchanges such as the mode identification response.
+func (s *Session) readLine(ctx context.Context) (string, error) {
+	data, err := s.readUntil(ctx, func(b byte) bool { return b == '\n' })
+	if err != nil {
+		return "", fmt.Errorf("iec: reading line: %w", err)
+	}
+	return string(data), nil
+}
+
+// WriteRequest writes p to the port, honouring ctx and ReadTimeout the same
+// way readUntil does.
+func (s *Session) WriteRequest(ctx context.Context, p []byte) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Port.Write(p)
+		done <- err
+	}()
+
+	timer := time.NewTimer(s.Settings.ReadTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return context.DeadlineExceeded
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("iec: writing request: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+package iec
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// pipePort is an io.ReadWriter backed by an in-memory pipe, letting tests
+// feed a Session bytes without a real serial port.
+type pipePort struct {
+	r io.Reader
+}
+
+func (p *pipePort) Read(b []byte) (int, error)  { return p.r.Read(b) }
+func (p *pipePort) Write(b []byte) (int, error) { return len(b), nil }
+
+// numGoroutines settles the scheduler and returns a steady-ish goroutine
+// count, to avoid false positives from goroutines still winding down.
+func numGoroutines(t *testing.T) int {
+	t.Helper()
+	runtime.Gosched()
+	time.Sleep(10 * time.Millisecond)
+	return runtime.NumGoroutine()
+}
+
+// TestReadUntilClosesReaderOnSuccess exercises the common case: readUntil
+// returning successfully must not leave its background reader goroutine
+// running forever. It previously only closed cancel on the error/timeout
+// paths, leaking one goroutine per successful read.
+func TestReadUntilClosesReaderOnSuccess(t *testing.T) {
+	pr, pw := io.Pipe()
+	s := NewSession(&pipePort{r: pr}, PortSettings{ReadTimeout: time.Second})
+
+	before := numGoroutines(t)
+
+	go pw.Write([]byte("hello\n"))
+
+	line, err := s.readLine(context.Background())
+	if err != nil {
+		t.Fatalf("readLine: %s", err.Error())
+	}
+	if line != "hello\n" {
+		t.Fatalf("readLine = %q, want %q", line, "hello\n")
+	}
+
+	// Unblock the background reader's in-flight Port.Read, the same way a
+	// real caller's defer port.Close() would, so it can observe the
+	// already-closed cancel channel and exit.
+	pw.Close()
+
+	var after int
+	for i := 0; i < 50; i++ {
+		after = numGoroutines(t)
+		if after <= before {
+			return
+		}
+	}
+	t.Fatalf("goroutine count after readLine = %d, want <= %d (before)", after, before)
+}
@@ -0,0 +1,34 @@
+package iec
+
+import "time"
+
+// PortSettings describes how to open and configure the serial port used to
+// talk to a meter.
+type PortSettings struct {
+	PortName string
+	BaudRate int
+	DataBits int
+	Parity   string
+	StopBits int
+
+	// Mode selects the IEC 62056-21 transmission mode to use. The zero
+	// value, ModeAuto, negotiates it via the identification exchange.
+	Mode Mode
+
+	// ReadTimeout bounds how long a single read or write operation may
+	// block before it is cancelled with context.DeadlineExceeded.
+	ReadTimeout time.Duration
+}
+
+// NewDefaultSettings returns the IEC 62056-21 mode A/B default port
+// configuration: 300 baud, 7 data bits, even parity, 1 stop bit.
+func NewDefaultSettings() PortSettings {
+	return PortSettings{
+		BaudRate:    300,
+		DataBits:    7,
+		Parity:      "E",
+		StopBits:    1,
+		Mode:        ModeAuto,
+		ReadTimeout: 5 * time.Second,
+	}
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Register holds a single OBIS-addressed reading taken from a meter.
+type Register struct {
+	OBIS  string
+	Value float64
+	Unit  string
+}
+
+// Measurement is one complete read of a meter: the time it was taken and
+// every OBIS register reported in that read. ID is a backend-assigned,
+// monotonically increasing identifier used to disambiguate cursor ties when
+// two Measurements share an identical Timestamp; it is empty for backends
+// that can't produce one.
+type Measurement struct {
+	ID        string
+	Timestamp time.Time
+	Registers []Register
+}
+
+// Filter narrows a Query to a time range and/or a set of OBIS codes. A zero
+// Filter (or zero field) means "no restriction".
+type Filter struct {
+	From, To time.Time
+	OBIS     []string
+}
+
+// Cursor identifies a position in a time-ordered, ID-tie-broken sequence of
+// Measurements. It is opaque to callers: produce and consume it only via
+// EncodeCursor/DecodeCursor.
+type Cursor struct {
+	LastTS time.Time `json:"last_ts"`
+	LastID string    `json:"last_id"`
+}
+
+// EncodeCursor base64-encodes c as an opaque pagination token.
+func EncodeCursor(c Cursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("model: encoding cursor: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor parses a token produced by EncodeCursor. An empty token
+// decodes to the zero Cursor, which Query treats as "start from the
+// beginning".
+func DecodeCursor(token string) (Cursor, error) {
+	var c Cursor
+	if token == "" {
+		return c, nil
+	}
+	b, err := base64.URLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("model: decoding cursor: %w", err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("model: decoding cursor: %w", err)
+	}
+	return c, nil
+}
+
+// QueryPage is one page of a cursor-paginated Query. Next/Prev are empty
+// when there is no further page in that direction.
+type QueryPage struct {
+	Items      []*Measurement
+	Next, Prev string
+}
+
+// MeasurementRepo stores and retrieves Measurements. Every method takes ctx
+// so a caller serving an HTTP request can bind a read to the request's
+// lifetime (client disconnect, service shutdown) instead of letting it run
+// unbounded; backends that can't interrupt an in-flight read (e.g. BoltDB's
+// transactions) still honour ctx by checking it before starting one.
+type MeasurementRepo interface {
+	GetAll(ctx context.Context) ([]*Measurement, error)
+	GetPage(ctx context.Context, page, size int) ([]*Measurement, error)
+	Get(ctx context.Context, key string) (*Measurement, error)
+	// GetByTimeRange returns every Measurement taken within [from, to].
+	GetByTimeRange(ctx context.Context, from, to time.Time) ([]*Measurement, error)
+	// Query returns a cursor-paginated, optionally filtered page of
+	// Measurements ordered by time. cursor is the opaque token from a
+	// previous QueryPage.Next/Prev, or "" to start from the beginning.
+	Query(ctx context.Context, filter Filter, cursor string, size int) (*QueryPage, error)
+}
+
+const (
+	// First selects the oldest stored Measurement.
+	First = "first"
+	// Last selects the most recently stored Measurement.
+	Last = "last"
+)
@@ -0,0 +1,321 @@
+package repo
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+var measurementsBucket = []byte("measurements")
+
+// BoltRepo is a model.MeasurementRepo backed by an embedded BoltDB file.
+// Measurements are stored as JSON values keyed by their big-endian Unix nano
+// timestamp, which keeps the bucket's natural (byte-sorted) key order equal
+// to chronological order.
+type BoltRepo struct {
+	db *bolt.DB
+}
+
+// OpenBolt opens (creating if necessary) the BoltDB file at path and its
+// measurements bucket.
+func OpenBolt(path string) (*BoltRepo, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("repo: opening bolt db %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(measurementsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo: creating bolt bucket: %w", err)
+	}
+	return &BoltRepo{db: db}, nil
+}
+
+func tsKey(ts time.Time) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(ts.UnixNano()))
+	return b
+}
+
+func (r *BoltRepo) Put(msm *model.Measurement) error {
+	b, err := json.Marshal(msm)
+	if err != nil {
+		return fmt.Errorf("repo: marshalling measurement: %w", err)
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(measurementsBucket).Put(tsKey(msm.Timestamp), b)
+	})
+}
+
+// checkCtx returns ctx's error if it is already done. BoltDB transactions
+// aren't context-aware, so this is the only cancellation BoltRepo honours:
+// it won't start a read after the caller has already given up.
+func checkCtx(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
+
+func (r *BoltRepo) GetAll(ctx context.Context) ([]*model.Measurement, error) {
+	if err := checkCtx(ctx); err != nil {
+		return nil, err
+	}
+	var out []*model.Measurement
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(measurementsBucket).ForEach(func(_, v []byte) error {
+			msm, err := unmarshalMeasurement(v)
+			if err != nil {
+				return err
+			}
+			out = append(out, msm)
+			return nil
+		})
+	})
+	return out, err
+}
+
+func (r *BoltRepo) GetPage(ctx context.Context, page, size int) ([]*model.Measurement, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("repo: size must be positive")
+	}
+	if err := checkCtx(ctx); err != nil {
+		return nil, err
+	}
+	var out []*model.Measurement
+	err := r.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(measurementsBucket).Cursor()
+		i := 0
+		skip := page * size
+		for k, v := c.First(); k != nil && len(out) < size; k, v = c.Next() {
+			if i < skip {
+				i++
+				continue
+			}
+			i++
+			msm, err := unmarshalMeasurement(v)
+			if err != nil {
+				return err
+			}
+			out = append(out, msm)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (r *BoltRepo) Get(ctx context.Context, key string) (*model.Measurement, error) {
+	if err := checkCtx(ctx); err != nil {
+		return nil, err
+	}
+	var out *model.Measurement
+	err := r.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(measurementsBucket).Cursor()
+		var k, v []byte
+		switch key {
+		case model.First:
+			k, v = c.First()
+		case model.Last:
+			k, v = c.Last()
+		default:
+			return fmt.Errorf("repo: unknown key %q", key)
+		}
+		if k == nil {
+			return nil
+		}
+		msm, err := unmarshalMeasurement(v)
+		if err != nil {
+			return err
+		}
+		out = msm
+		return nil
+	})
+	return out, err
+}
+
+func (r *BoltRepo) GetByTimeRange(ctx context.Context, from, to time.Time) ([]*model.Measurement, error) {
+	if err := checkCtx(ctx); err != nil {
+		return nil, err
+	}
+	var out []*model.Measurement
+	err := r.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(measurementsBucket).Cursor()
+		min, max := tsKey(from), tsKey(to)
+		for k, v := c.Seek(min); k != nil && string(k) <= string(max); k, v = c.Next() {
+			msm, err := unmarshalMeasurement(v)
+			if err != nil {
+				return err
+			}
+			out = append(out, msm)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func unmarshalMeasurement(v []byte) (*model.Measurement, error) {
+	msm := &model.Measurement{}
+	if err := json.Unmarshal(v, msm); err != nil {
+		return nil, fmt.Errorf("repo: unmarshalling measurement: %w", err)
+	}
+	return msm, nil
+}
+
+// Query implements model.MeasurementRepo.Query. Measurements are keyed
+// one-per-timestamp, so two entries sharing a Timestamp can't occur and
+// LastID is set only for symmetry with the cursor's documented shape.
+func (r *BoltRepo) Query(ctx context.Context, filter model.Filter, cursor string, size int) (*model.QueryPage, error) {
+	if size <= 0 {
+		size = 50
+	}
+	if err := checkCtx(ctx); err != nil {
+		return nil, err
+	}
+	c, err := model.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*model.Measurement
+	var hasNext bool
+
+	err = r.db.View(func(tx *bolt.Tx) error {
+		cur := tx.Bucket(measurementsBucket).Cursor()
+		k, v := seekAfter(cur, c.LastTS)
+		for ; k != nil; k, v = cur.Next() {
+			ts := keyTs(k)
+			if !filter.To.IsZero() && ts.After(filter.To) {
+				return nil
+			}
+			if !filter.From.IsZero() && ts.Before(filter.From) {
+				continue
+			}
+			msm, err := unmarshalMeasurement(v)
+			if err != nil {
+				return err
+			}
+			msm = filterRegisters(msm, filter.OBIS)
+			if len(filter.OBIS) > 0 && len(msm.Registers) == 0 {
+				continue
+			}
+			if len(items) == size {
+				hasNext = true
+				return nil
+			}
+			msm.ID = ts.Format(time.RFC3339Nano)
+			items = append(items, msm)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.QueryPage{Items: items}
+	if len(items) == 0 {
+		return page, nil
+	}
+	if hasNext {
+		last := items[len(items)-1]
+		if page.Next, err = model.EncodeCursor(model.Cursor{LastTS: last.Timestamp, LastID: last.ID}); err != nil {
+			return nil, err
+		}
+	}
+	// To page backward, walk from the current page's first key towards the
+	// start of the bucket, counting matching entries: the first `size` of
+	// those (reversed to ascending order) ARE the previous page; the
+	// (size+1)-th, if present, anchors a cursor that reproduces exactly
+	// that page when queried forward again.
+	var backCount int
+	var anchor time.Time
+	var hasAnchor bool
+	err = r.db.View(func(tx *bolt.Tx) error {
+		cur := tx.Bucket(measurementsBucket).Cursor()
+		cur.Seek(tsKey(items[0].Timestamp))
+		for k, v := cur.Prev(); k != nil; k, v = cur.Prev() {
+			ts := keyTs(k)
+			if !filter.From.IsZero() && ts.Before(filter.From) {
+				break
+			}
+			if len(filter.OBIS) > 0 {
+				msm, err := unmarshalMeasurement(v)
+				if err != nil {
+					return err
+				}
+				if len(filterRegisters(msm, filter.OBIS).Registers) == 0 {
+					continue
+				}
+			}
+			backCount++
+			if backCount == size+1 {
+				anchor, hasAnchor = ts, true
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case hasAnchor:
+		if page.Prev, err = model.EncodeCursor(model.Cursor{LastTS: anchor}); err != nil {
+			return nil, err
+		}
+	case backCount > 0:
+		// The previous page starts at the very beginning of the result
+		// set: anchor on the zero cursor rather than "", which is
+		// reserved for "there is no previous page".
+		if page.Prev, err = model.EncodeCursor(model.Cursor{}); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// seekAfter positions cur strictly after ts (or at the start, if ts is
+// zero) and returns the key/value pair it lands on.
+func seekAfter(cur *bolt.Cursor, ts time.Time) ([]byte, []byte) {
+	if ts.IsZero() {
+		return cur.First()
+	}
+	k, v := cur.Seek(tsKey(ts))
+	if k != nil && string(k) == string(tsKey(ts)) {
+		return cur.Next()
+	}
+	return k, v
+}
+
+func keyTs(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k)))
+}
+
+// filterRegisters restricts msm's Registers to the given OBIS codes,
+// leaving it untouched if obis is empty.
+func filterRegisters(msm *model.Measurement, obis []string) *model.Measurement {
+	if len(obis) == 0 {
+		return msm
+	}
+	allowed := make(map[string]bool, len(obis))
+	for _, o := range obis {
+		allowed[o] = true
+	}
+	var regs []model.Register
+	for _, reg := range msm.Registers {
+		if allowed[reg.OBIS] {
+			regs = append(regs, reg)
+		}
+	}
+	msm.Registers = regs
+	return msm
+}
@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+)
+
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS measurements (
+	id    SERIAL PRIMARY KEY,
+	ts    TIMESTAMPTZ NOT NULL,
+	obis  TEXT NOT NULL,
+	value DOUBLE PRECISION NOT NULL,
+	unit  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_measurements_ts ON measurements(ts);
+CREATE INDEX IF NOT EXISTS idx_measurements_obis ON measurements(obis);
+`
+
+// OpenPostgres connects to the Postgres database described by dsn and
+// applies schema migrations.
+func OpenPostgres(dsn string) (*sqlRepo, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("repo: opening postgres db: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("repo: connecting to postgres: %w", err)
+	}
+	if _, err := db.Exec(postgresSchema); err != nil {
+		return nil, fmt.Errorf("repo: migrating postgres schema: %w", err)
+	}
+	return &sqlRepo{
+		db: db,
+		placeholder: func(n int) string {
+			return fmt.Sprintf("$%d", n)
+		},
+	}, nil
+}
@@ -0,0 +1,185 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+// Query implements model.MeasurementRepo.Query. Measurements are grouped by
+// distinct timestamp (see scanMeasurements), which makes two groups sharing
+// a Timestamp impossible by construction; LastID therefore never needs to
+// break a tie here and only LastTS is consulted.
+func (r *sqlRepo) Query(ctx context.Context, filter model.Filter, cursor string, size int) (*model.QueryPage, error) {
+	if size <= 0 {
+		size = 50
+	}
+	c, err := model.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	conds, args := r.filterConds(filter)
+
+	fwdConds, fwdArgs := conds, args
+	if !c.LastTS.IsZero() {
+		fwdConds = append(append([]string{}, conds...), fmt.Sprintf("ts > %s", r.placeholder(len(args)+1)))
+		fwdArgs = append(append([]interface{}{}, args...), c.LastTS)
+	}
+	groups, err := r.pageGroups(ctx, fwdConds, fwdArgs, size+1, false)
+	if err != nil {
+		return nil, err
+	}
+	hasNext := len(groups) > size
+	if hasNext {
+		groups = groups[:size]
+	}
+	if len(groups) == 0 {
+		return &model.QueryPage{}, nil
+	}
+
+	items, err := r.getByGroups(ctx, groups, filter.OBIS)
+	if err != nil {
+		return nil, err
+	}
+	page := &model.QueryPage{Items: items}
+
+	if hasNext {
+		last := groups[len(groups)-1]
+		if page.Next, err = model.EncodeCursor(model.Cursor{LastTS: last.ts, LastID: strconv.FormatInt(last.id, 10)}); err != nil {
+			return nil, err
+		}
+	}
+
+	// To page backward, fetch up to size+1 groups strictly before the
+	// current page, nearest-first: the first `size` of those (reversed to
+	// ascending order) ARE the previous page; the (size+1)-th, if present,
+	// anchors a cursor that reproduces exactly that page when queried
+	// forward again.
+	bwdConds := append(append([]string{}, conds...), fmt.Sprintf("ts < %s", r.placeholder(len(args)+1)))
+	bwdArgs := append(append([]interface{}{}, args...), groups[0].ts)
+	prevGroups, err := r.pageGroups(ctx, bwdConds, bwdArgs, size+1, true)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(prevGroups) > size:
+		anchor := prevGroups[size]
+		if page.Prev, err = model.EncodeCursor(model.Cursor{LastTS: anchor.ts, LastID: strconv.FormatInt(anchor.id, 10)}); err != nil {
+			return nil, err
+		}
+	case len(prevGroups) > 0:
+		// The previous page starts at the very beginning of the result
+		// set: anchor on the zero cursor rather than "", which is
+		// reserved for "there is no previous page".
+		if page.Prev, err = model.EncodeCursor(model.Cursor{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+type tsGroup struct {
+	ts time.Time
+	id int64
+}
+
+// pageGroups returns up to limit distinct-timestamp groups matching conds,
+// each paired with the smallest row id sharing that timestamp.
+func (r *sqlRepo) pageGroups(ctx context.Context, conds []string, args []interface{}, limit int, desc bool) ([]tsGroup, error) {
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+	order := "ASC"
+	if desc {
+		order = "DESC"
+	}
+	q := fmt.Sprintf(`SELECT ts, MIN(id) FROM measurements %s GROUP BY ts ORDER BY ts %s LIMIT %s`,
+		where, order, r.placeholder(len(args)+1))
+
+	rows, err := r.db.QueryContext(ctx, q, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("repo: querying measurement groups: %w", err)
+	}
+	defer rows.Close()
+
+	var out []tsGroup
+	for rows.Next() {
+		var g tsGroup
+		if err := rows.Scan(&g.ts, &g.id); err != nil {
+			return nil, fmt.Errorf("repo: scanning measurement group: %w", err)
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+// getByGroups fetches the full rows for groups, optionally restricted to
+// obis codes, and returns one Measurement per group with ID set.
+func (r *sqlRepo) getByGroups(ctx context.Context, groups []tsGroup, obis []string) ([]*model.Measurement, error) {
+	var args []interface{}
+	phs := make([]string, len(groups))
+	for i, g := range groups {
+		phs[i] = r.placeholder(len(args) + 1)
+		args = append(args, g.ts)
+	}
+	conds := []string{fmt.Sprintf("ts IN (%s)", strings.Join(phs, ", "))}
+	if len(obis) > 0 {
+		ophs := make([]string, len(obis))
+		for i, o := range obis {
+			ophs[i] = r.placeholder(len(args) + 1)
+			args = append(args, o)
+		}
+		conds = append(conds, fmt.Sprintf("obis IN (%s)", strings.Join(ophs, ", ")))
+	}
+
+	q := fmt.Sprintf(`SELECT ts, obis, value, unit FROM measurements WHERE %s ORDER BY ts, obis`,
+		strings.Join(conds, " AND "))
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repo: querying measurements by group: %w", err)
+	}
+	defer rows.Close()
+
+	msms, err := scanMeasurements(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[time.Time]int64, len(groups))
+	for _, g := range groups {
+		ids[g.ts] = g.id
+	}
+	for _, msm := range msms {
+		msm.ID = strconv.FormatInt(ids[msm.Timestamp], 10)
+	}
+	return msms, nil
+}
+
+func (r *sqlRepo) filterConds(filter model.Filter) ([]string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if !filter.From.IsZero() {
+		conds = append(conds, fmt.Sprintf("ts >= %s", r.placeholder(len(args)+1)))
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conds = append(conds, fmt.Sprintf("ts <= %s", r.placeholder(len(args)+1)))
+		args = append(args, filter.To)
+	}
+	if len(filter.OBIS) > 0 {
+		phs := make([]string, len(filter.OBIS))
+		for i, o := range filter.OBIS {
+			phs[i] = r.placeholder(len(args) + 1)
+			args = append(args, o)
+		}
+		conds = append(conds, fmt.Sprintf("obis IN (%s)", strings.Join(phs, ", ")))
+	}
+	return conds, args
+}
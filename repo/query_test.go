@@ -0,0 +1,125 @@
+package repo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+// assertPagingForwardThenBack pages through r in steps of size until it runs
+// out of pages, then follows the last page's Prev cursor back to the start
+// and asserts every page is reproduced identically in reverse.
+func assertPagingForwardThenBack(t *testing.T, r model.MeasurementRepo, size int) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	var forward []*model.QueryPage
+	cursor := ""
+	for {
+		page, err := r.Query(ctx, model.Filter{}, cursor, size)
+		if err != nil {
+			t.Fatalf("Query forward: %s", err.Error())
+		}
+		forward = append(forward, page)
+		if page.Next == "" {
+			break
+		}
+		cursor = page.Next
+	}
+	if len(forward) < 2 {
+		t.Fatalf("expected at least 2 pages, got %d", len(forward))
+	}
+
+	for i := len(forward) - 1; i > 0; i-- {
+		back, err := r.Query(ctx, model.Filter{}, forward[i].Prev, size)
+		if err != nil {
+			t.Fatalf("Query backward from page %d: %s", i, err.Error())
+		}
+		if !reflect.DeepEqual(back.Items, forward[i-1].Items) {
+			t.Fatalf("page %d reached via Prev = %+v, want %+v", i-1, back.Items, forward[i-1].Items)
+		}
+	}
+}
+
+func TestSQLQueryPagingForwardThenBack(t *testing.T) {
+	r := newTestSQLiteRepo(t)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 11; i++ {
+		msm := &model.Measurement{
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Registers: []model.Register{{OBIS: "1.8.0", Value: float64(i), Unit: "kWh"}},
+		}
+		if err := insertMeasurement(r.db, r.placeholder, msm); err != nil {
+			t.Fatalf("insertMeasurement %d: %s", i, err.Error())
+		}
+	}
+
+	assertPagingForwardThenBack(t, r, 3)
+}
+
+func TestBoltQueryPagingForwardThenBack(t *testing.T) {
+	r := newTestBoltRepo(t)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 11; i++ {
+		msm := &model.Measurement{
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Registers: []model.Register{{OBIS: "1.8.0", Value: float64(i), Unit: "kWh"}},
+		}
+		if err := r.Put(msm); err != nil {
+			t.Fatalf("Put %d: %s", i, err.Error())
+		}
+	}
+
+	assertPagingForwardThenBack(t, r, 3)
+}
+
+// seedOBISMix writes 5 measurements, 3 of which have no register matching
+// "1.8.0", via put.
+func seedOBISMix(t *testing.T, put func(*model.Measurement) error) {
+	t.Helper()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	obis := []string{"2.8.0", "2.8.0", "1.8.0", "2.8.0", "1.8.0"}
+	for i, o := range obis {
+		msm := &model.Measurement{
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Registers: []model.Register{{OBIS: o, Value: float64(i), Unit: "kWh"}},
+		}
+		if err := put(msm); err != nil {
+			t.Fatalf("seeding measurement %d: %s", i, err.Error())
+		}
+	}
+}
+
+func TestSQLQueryFiltersByOBIS(t *testing.T) {
+	r := newTestSQLiteRepo(t)
+	seedOBISMix(t, func(msm *model.Measurement) error {
+		return insertMeasurement(r.db, r.placeholder, msm)
+	})
+
+	page, err := r.Query(context.Background(), model.Filter{OBIS: []string{"1.8.0"}}, "", 10)
+	if err != nil {
+		t.Fatalf("Query: %s", err.Error())
+	}
+	if len(page.Items) != 2 {
+		t.Fatalf("Query with OBIS filter returned %d items, want 2", len(page.Items))
+	}
+}
+
+func TestBoltQueryFiltersByOBIS(t *testing.T) {
+	r := newTestBoltRepo(t)
+	seedOBISMix(t, r.Put)
+
+	page, err := r.Query(context.Background(), model.Filter{OBIS: []string{"1.8.0"}}, "", 10)
+	if err != nil {
+		t.Fatalf("Query: %s", err.Error())
+	}
+	if len(page.Items) != 2 {
+		t.Fatalf("Query with OBIS filter returned %d items, want 2", len(page.Items))
+	}
+}
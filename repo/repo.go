@@ -0,0 +1,37 @@
+// Package repo provides interchangeable persistent model.MeasurementRepo
+// backends, selected at runtime via a URL-scheme DSN.
+package repo
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+// Open parses dsn's URL scheme and returns the matching MeasurementRepo
+// backend: sqlite://, postgres:// (or postgresql://) and bolt://.
+func Open(dsn string) (model.MeasurementRepo, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("repo: parsing dsn: %w", err)
+	}
+
+	switch u.Scheme {
+	case "sqlite":
+		return OpenSQLite(dsnPath(u))
+	case "postgres", "postgresql":
+		return OpenPostgres(dsn)
+	case "bolt":
+		return OpenBolt(dsnPath(u))
+	default:
+		return nil, fmt.Errorf("repo: unknown dsn scheme %q", u.Scheme)
+	}
+}
+
+// dsnPath turns a file-backed dsn (sqlite://./cache.db, bolt:///var/x.db)
+// into a filesystem path, so both the relative (host-relative) and the
+// absolute (rooted path) forms resolve correctly.
+func dsnPath(u *url.URL) string {
+	return u.Host + u.Path
+}
@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS measurements (
+	id    INTEGER PRIMARY KEY AUTOINCREMENT,
+	ts    TIMESTAMP NOT NULL,
+	obis  TEXT NOT NULL,
+	value DOUBLE PRECISION NOT NULL,
+	unit  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_measurements_ts ON measurements(ts);
+CREATE INDEX IF NOT EXISTS idx_measurements_obis ON measurements(obis);
+`
+
+// sqlRepo implements model.MeasurementRepo against a database/sql handle.
+// Each row is one OBIS register; a Measurement is every row sharing a
+// timestamp. placeholder formats the n-th positional bind parameter for the
+// underlying driver ("?" for sqlite, "$1"-style for postgres), since the
+// queries themselves are otherwise identical across backends.
+type sqlRepo struct {
+	db          *sql.DB
+	placeholder func(n int) string
+}
+
+func (r *sqlRepo) GetAll(ctx context.Context) ([]*model.Measurement, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT ts, obis, value, unit FROM measurements ORDER BY ts, obis`)
+	if err != nil {
+		return nil, fmt.Errorf("repo: querying all measurements: %w", err)
+	}
+	defer rows.Close()
+	return scanMeasurements(rows)
+}
+
+func (r *sqlRepo) GetPage(ctx context.Context, page, size int) ([]*model.Measurement, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("repo: size must be positive")
+	}
+	q := fmt.Sprintf(`
+		SELECT ts, obis, value, unit FROM measurements
+		WHERE ts IN (
+			SELECT DISTINCT ts FROM measurements ORDER BY ts LIMIT %s OFFSET %s
+		)
+		ORDER BY ts, obis`, r.placeholder(1), r.placeholder(2))
+	rows, err := r.db.QueryContext(ctx, q, size, page*size)
+	if err != nil {
+		return nil, fmt.Errorf("repo: querying page %d: %w", page, err)
+	}
+	defer rows.Close()
+	return scanMeasurements(rows)
+}
+
+func (r *sqlRepo) Get(ctx context.Context, key string) (*model.Measurement, error) {
+	var order string
+	switch key {
+	case model.First:
+		order = "ASC"
+	case model.Last:
+		order = "DESC"
+	default:
+		return nil, fmt.Errorf("repo: unknown key %q", key)
+	}
+
+	var ts time.Time
+	q := fmt.Sprintf(`SELECT ts FROM measurements ORDER BY ts %s LIMIT 1`, order)
+	if err := r.db.QueryRowContext(ctx, q).Scan(&ts); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repo: querying %s measurement: %w", key, err)
+	}
+
+	return r.getByTimestamp(ctx, ts)
+}
+
+func (r *sqlRepo) GetByTimeRange(ctx context.Context, from, to time.Time) ([]*model.Measurement, error) {
+	q := fmt.Sprintf(`
+		SELECT ts, obis, value, unit FROM measurements
+		WHERE ts >= %s AND ts <= %s
+		ORDER BY ts, obis`, r.placeholder(1), r.placeholder(2))
+	rows, err := r.db.QueryContext(ctx, q, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("repo: querying time range: %w", err)
+	}
+	defer rows.Close()
+	return scanMeasurements(rows)
+}
+
+func (r *sqlRepo) getByTimestamp(ctx context.Context, ts time.Time) (*model.Measurement, error) {
+	q := fmt.Sprintf(`SELECT ts, obis, value, unit FROM measurements WHERE ts = %s ORDER BY obis`, r.placeholder(1))
+	rows, err := r.db.QueryContext(ctx, q, ts)
+	if err != nil {
+		return nil, fmt.Errorf("repo: querying measurement at %s: %w", ts, err)
+	}
+	defer rows.Close()
+
+	msms, err := scanMeasurements(rows)
+	if err != nil || len(msms) == 0 {
+		return nil, err
+	}
+	return msms[0], nil
+}
+
+// scanMeasurements groups (ts, obis, value, unit) rows, which are assumed to
+// be ordered by ts, into one Measurement per distinct timestamp.
+func scanMeasurements(rows *sql.Rows) ([]*model.Measurement, error) {
+	var out []*model.Measurement
+	var cur *model.Measurement
+
+	for rows.Next() {
+		var ts time.Time
+		var reg model.Register
+		if err := rows.Scan(&ts, &reg.OBIS, &reg.Value, &reg.Unit); err != nil {
+			return nil, fmt.Errorf("repo: scanning measurement row: %w", err)
+		}
+
+		if cur == nil || !cur.Timestamp.Equal(ts) {
+			cur = &model.Measurement{Timestamp: ts}
+			out = append(out, cur)
+		}
+		cur.Registers = append(cur.Registers, reg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: iterating measurement rows: %w", err)
+	}
+	return out, nil
+}
+
+func insertMeasurement(db *sql.DB, placeholder func(n int) string, msm *model.Measurement) error {
+	q := fmt.Sprintf(`INSERT INTO measurements (ts, obis, value, unit) VALUES (%s, %s, %s, %s)`,
+		placeholder(1), placeholder(2), placeholder(3), placeholder(4))
+	for _, reg := range msm.Registers {
+		if _, err := db.Exec(q, msm.Timestamp, reg.OBIS, reg.Value, reg.Unit); err != nil {
+			return fmt.Errorf("repo: inserting register %s: %w", reg.OBIS, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+// OpenSQLite opens (creating if necessary) a CGO-free SQLite database at
+// path and applies schema migrations.
+func OpenSQLite(path string) (*sqlRepo, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("repo: opening sqlite db %s: %w", path, err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return nil, fmt.Errorf("repo: migrating sqlite schema: %w", err)
+	}
+	return &sqlRepo{
+		db:          db,
+		placeholder: func(int) string { return "?" },
+	}, nil
+}
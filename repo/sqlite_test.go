@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+func newTestSQLiteRepo(t *testing.T) *sqlRepo {
+	t.Helper()
+	r, err := OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("OpenSQLite: %s", err.Error())
+	}
+	return r
+}
+
+func TestSQLiteRepoCRUD(t *testing.T) {
+	r := newTestSQLiteRepo(t)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		msm := &model.Measurement{
+			Timestamp: base.Add(time.Duration(i) * time.Hour),
+			Registers: []model.Register{{OBIS: "1.8.0", Value: float64(i), Unit: "kWh"}},
+		}
+		if err := insertMeasurement(r.db, r.placeholder, msm); err != nil {
+			t.Fatalf("insertMeasurement %d: %s", i, err.Error())
+		}
+	}
+
+	ctx := context.Background()
+
+	all, err := r.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %s", err.Error())
+	}
+	if len(all) != 3 {
+		t.Fatalf("GetAll returned %d measurements, want 3", len(all))
+	}
+
+	first, err := r.Get(ctx, model.First)
+	if err != nil {
+		t.Fatalf("Get(First): %s", err.Error())
+	}
+	if !first.Timestamp.Equal(base) {
+		t.Errorf("Get(First) timestamp = %s, want %s", first.Timestamp, base)
+	}
+
+	last, err := r.Get(ctx, model.Last)
+	if err != nil {
+		t.Fatalf("Get(Last): %s", err.Error())
+	}
+	if !last.Timestamp.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("Get(Last) timestamp = %s, want %s", last.Timestamp, base.Add(2*time.Hour))
+	}
+
+	page, err := r.GetPage(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("GetPage: %s", err.Error())
+	}
+	if len(page) != 1 || !page[0].Timestamp.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("GetPage(1, 2) = %+v, want the third measurement", page)
+	}
+
+	ranged, err := r.GetByTimeRange(ctx, base.Add(time.Hour), base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("GetByTimeRange: %s", err.Error())
+	}
+	if len(ranged) != 2 {
+		t.Fatalf("GetByTimeRange returned %d measurements, want 2", len(ranged))
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mariuszjedrzejewski/iec62056/actors"
@@ -18,6 +20,9 @@ import (
 var (
 	// ErrBadParameter returned for bad parameters
 	ErrBadParameter = errors.New("parameter error")
+	// ErrAlreadyStarted is returned by HTTPLocalService.Start if the
+	// service is already running.
+	ErrAlreadyStarted = errors.New("service: already started")
 )
 
 // HTTPLocalService services requests for the local measurement cache
@@ -25,6 +30,14 @@ type HTTPLocalService struct {
 	listenAddress string
 	localRepo     model.MeasurementRepo
 	server        *http.Server
+
+	mu      sync.Mutex
+	started bool
+	ready   chan struct{}
+
+	rootCtx    context.Context
+	cancelRoot context.CancelFunc
+	inFlight   sync.WaitGroup
 }
 
 type GetAllHandler struct {
@@ -33,71 +46,76 @@ type GetAllHandler struct {
 
 type MeasurementsResponse struct {
 	Data interface{} `json:",omitempty"`
+	Next string      `json:"next,omitempty"`
+	Prev string      `json:"prev,omitempty"`
 }
 
-type errPagination struct {
+type errQueryParams struct {
 	strings.Builder
 }
 
-func (s *errPagination) Error() string {
-	return "bad pagination parameters\n" + s.String()
+func (s *errQueryParams) Error() string {
+	return "bad query parameters\n" + s.String()
 }
 
-type pagination struct {
-	page, size int
-	err        *errPagination
+// queryParams holds the parsed ?from=&to=&obis=&cursor=&size= parameters
+// for the cursor-paginated /measurements endpoint.
+type queryParams struct {
+	filter model.Filter
+	cursor string
+	size   int
+	err    *errQueryParams
 }
 
-func NewPagination(r *http.Request) *pagination {
-	p := new(pagination)
-	p.getParams(r)
-	return p
+func newQueryParams(r *http.Request) *queryParams {
+	q := new(queryParams)
+	q.getParams(r)
+	return q
 }
 
-func (p *pagination) getParams(r *http.Request) {
-	page := r.FormValue("page")
-	size := r.FormValue("size")
-
-	p.page = 0
-	p.size = 0
+func (q *queryParams) getParams(r *http.Request) {
+	serr := &errQueryParams{}
 
-	serr := &errPagination{}
-	if len(page) != 0 {
-		if v, err := strconv.Atoi(r.FormValue("page")); err != nil {
-			fmt.Fprintf(serr, "\tpage parameter error: %s\n", err.Error())
+	if from := r.FormValue("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			fmt.Fprintf(serr, "\tfrom parameter error: %s\n", err.Error())
 		} else {
-			if v < 0 {
-				fmt.Fprint(serr, "\tpage parameter cannog be negative\n")
-			} else {
-				p.page = v
-			}
+			q.filter.From = t
 		}
 	}
-	if len(size) > 0 {
-		if v, err := strconv.Atoi(r.FormValue("size")); err != nil {
-			fmt.Fprintf(serr, "\tsize parameter error: %s\n", err.Error())
+	if to := r.FormValue("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			fmt.Fprintf(serr, "\tto parameter error: %s\n", err.Error())
 		} else {
-			if v < 0 {
-				fmt.Fprint(serr, "\tsize parameter cannot be negative\n")
-			} else {
-				p.size = v
-			}
+			q.filter.To = t
 		}
 	}
-	if p.page > 0 && p.size == 0 {
-		fmt.Fprint(serr, "\tnon zero page parameter requires non zero limit\n")
+	if obis := r.FormValue("obis"); obis != "" {
+		q.filter.OBIS = strings.Split(obis, ",")
 	}
-	if serr.Len() > 0 {
-		p.err = serr
+	q.cursor = r.FormValue("cursor")
+
+	if size := r.FormValue("size"); size != "" {
+		v, err := strconv.Atoi(size)
+		if err != nil {
+			fmt.Fprintf(serr, "\tsize parameter error: %s\n", err.Error())
+		} else if v < 0 {
+			fmt.Fprint(serr, "\tsize parameter cannot be negative\n")
+		} else {
+			q.size = v
+		}
 	}
-}
 
-func (p *pagination) paginate() bool {
-	return p.err == nil && p.size > 0
+	if serr.Len() > 0 {
+		q.err = serr
+	}
 }
 
 func NewHttpLocalService(address string, repo model.MeasurementRepo) Service {
 	sm := &http.ServeMux{}
+	rootCtx, cancelRoot := context.WithCancel(context.Background())
 	svc := &HTTPLocalService{
 		listenAddress: address,
 		localRepo:     repo,
@@ -105,6 +123,9 @@ func NewHttpLocalService(address string, repo model.MeasurementRepo) Service {
 			Handler: sm,
 			Addr:    address,
 		},
+		ready:      make(chan struct{}),
+		rootCtx:    rootCtx,
+		cancelRoot: cancelRoot,
 	}
 	gah := &GetAllHandler{
 		server: svc,
@@ -117,7 +138,7 @@ func NewHttpLocalService(address string, repo model.MeasurementRepo) Service {
 type requestContext struct {
 	first, last bool
 	err         error
-	pag         *pagination
+	query       *queryParams
 }
 
 const (
@@ -145,21 +166,21 @@ func getContext(r *http.Request) *requestContext {
 		return c
 	}
 
-	// Determine the pagination parameters.
+	// Determine the query parameters.
 	if c.err = r.ParseForm(); c.err != nil {
 		return c
 	}
-	var pag *pagination
-	if pag = NewPagination(r); pag.err != nil {
-		c.err = pag.err
+	q := newQueryParams(r)
+	if q.err != nil {
+		c.err = q.err
 		return c
 	}
-	c.pag = pag
+	c.query = q
 	return c
 }
 
-func get(a *actors.PagerActor, key string) (*MeasurementsResponse, error) {
-	msm, err := a.Get(key)
+func get(ctx context.Context, a *actors.PagerActor, key string) (*MeasurementsResponse, error) {
+	msm, err := a.Get(ctx, key)
 	if err != nil {
 		return nil, err
 	}
@@ -173,55 +194,63 @@ func get(a *actors.PagerActor, key string) (*MeasurementsResponse, error) {
 	}, nil
 }
 
-func getPage(a *actors.PagerActor, pag *pagination) (*MeasurementsResponse, error) {
-	msm, err := a.GetPage(pag.page, pag.size)
+func query(ctx context.Context, a *actors.PagerActor, q *queryParams) (*MeasurementsResponse, error) {
+	page, err := a.Query(ctx, q.filter, q.cursor, q.size)
 	if err != nil {
 		return nil, err
 	}
 
 	return &MeasurementsResponse{
-		Data: msm,
+		Data: page.Items,
+		Next: page.Next,
+		Prev: page.Prev,
 	}, nil
 }
 
-func getAll(a *actors.PagerActor) (*MeasurementsResponse, error) {
-	msm, err := a.GetAll()
-	if err != nil {
-		return nil, err
-	}
-
-	return &MeasurementsResponse{
-		Data: msm,
-	}, nil
-
+// requestCtx merges r.Context(), so a client disconnect aborts the read, with
+// root, so Stop on the owning service aborts it too; it is cancelled as soon
+// as either parent is done.
+func requestCtx(r *http.Request, root context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(r.Context())
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-root.Done():
+			cancel()
+		}
+	}()
+	return ctx, cancel
 }
 
 // ServeHTTP reads all entries from the local repo and returns the JSON.
 func (h *GetAllHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ctx := getContext(r)
-	if ctx.err != nil {
+	h.server.inFlight.Add(1)
+	defer h.server.inFlight.Done()
+
+	rc := getContext(r)
+	if rc.err != nil {
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
 
+	ctx, cancel := requestCtx(r, h.server.rootCtx)
+	defer cancel()
+
 	var a = &actors.PagerActor{
 		Repo: h.server.localRepo,
 	}
 	var mr *MeasurementsResponse
 	var err error
 	switch {
-	case ctx.first:
+	case rc.first:
 		log.Print("GetAll: getFirst")
-		mr, err = get(a, model.First)
-	case ctx.last:
+		mr, err = get(ctx, a, model.First)
+	case rc.last:
 		log.Print("GetAll: getLast")
-		mr, err = get(a, model.Last)
-	case ctx.pag != nil && ctx.pag.paginate():
-		log.Print("GetAll: getPage")
-		mr, err = getPage(a, ctx.pag)
+		mr, err = get(ctx, a, model.Last)
 	default:
-		log.Print("GetAll: getAll")
-		mr, err = getAll(a)
+		log.Print("GetAll: query")
+		mr, err = query(ctx, a, rc.query)
 	}
 	// Get the data.
 	if err != nil {
@@ -242,25 +271,53 @@ func (h *GetAllHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	log.Printf("writing measurements response...done")
 }
 
-// Start starts the HTTP server on the given address and port.
+// Ready returns a channel that is closed once Start has successfully bound
+// the listener and handed it off to Serve. Callers that need a readiness
+// probe (systemd, k8s) should block on it rather than guessing how long
+// startup takes.
+func (s *HTTPLocalService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Start binds the listener synchronously, so bind errors (e.g. address
+// already in use) are returned immediately, then serves on it in the
+// background. Calling Start a second time returns ErrAlreadyStarted without
+// touching the running server.
 func (s *HTTPLocalService) Start(ctx context.Context) error {
-	var err error
-	var done = make(chan struct{})
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	ln, err := net.Listen("tcp", s.listenAddress)
+	if err != nil {
+		s.mu.Lock()
+		s.started = false
+		s.mu.Unlock()
+		return fmt.Errorf("service: binding %s: %w", s.listenAddress, err)
+	}
+
+	close(s.ready)
+
 	go func() {
-		err = s.server.ListenAndServe()
-		close(done)
+		if err := s.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Printf("http local service: serve error: %s\n", err.Error())
+		}
 	}()
-	select {
-	case <-done:
-		return err
-	case <-time.After(time.Second):
-		return nil
-	}
+
+	return nil
 }
 
+// Stop shuts down the HTTP server, honouring ctx's deadline, then cancels
+// rootCtx so any repository read still in flight is aborted rather than left to
+// run to completion, and waits for all in-flight requests to actually
+// finish.
 func (s *HTTPLocalService) Stop(ctx context.Context) error {
-	if err := s.server.Shutdown(ctx); err != nil {
-		return err
-	}
-	return nil
+	err := s.server.Shutdown(ctx)
+	s.cancelRoot()
+	s.inFlight.Wait()
+	return err
 }
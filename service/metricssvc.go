@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mariuszjedrzejewski/iec62056/adapters/meter"
+	"github.com/mariuszjedrzejewski/iec62056/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// HTTPMetricsService exposes meter readings in Prometheus text exposition
+// format at /metrics, alongside HTTPLocalService's JSON measurements API.
+type HTTPMetricsService struct {
+	listenAddress string
+	server        *http.Server
+	collector     *meterCollector
+
+	mu      sync.Mutex
+	started bool
+	ready   chan struct{}
+}
+
+// NewHTTPMetricsService wires a meterCollector for m into a fresh registry
+// and returns a Service serving it at /metrics on address.
+func NewHTTPMetricsService(address string, m *meter.Meter, meterPort string) Service {
+	c := newMeterCollector(m, meterPort)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	sm := &http.ServeMux{}
+	sm.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	return &HTTPMetricsService{
+		listenAddress: address,
+		collector:     c,
+		server: &http.Server{
+			Handler: sm,
+			Addr:    address,
+		},
+		ready: make(chan struct{}),
+	}
+}
+
+// Get runs a meter read through the underlying meter.Meter and records its
+// outcome, so a subsequent /metrics scrape reflects this poll. The regular
+// polling loop should call this instead of calling meter.Meter.Get
+// directly.
+func (s *HTTPMetricsService) Get(ctx context.Context) (*model.Measurement, error) {
+	return s.collector.Get(ctx)
+}
+
+// Ready returns a channel that is closed once Start has successfully bound
+// the listener and handed it off to Serve. Callers that need a readiness
+// probe (systemd, k8s) should block on it rather than guessing how long
+// startup takes.
+func (s *HTTPMetricsService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Start binds the listener synchronously, so bind errors (e.g. address
+// already in use) are returned immediately, then serves on it in the
+// background. Calling Start a second time returns ErrAlreadyStarted without
+// touching the running server.
+func (s *HTTPMetricsService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	ln, err := net.Listen("tcp", s.listenAddress)
+	if err != nil {
+		s.mu.Lock()
+		s.started = false
+		s.mu.Unlock()
+		return fmt.Errorf("service: binding %s: %w", s.listenAddress, err)
+	}
+
+	close(s.ready)
+
+	go func() {
+		if err := s.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Printf("http metrics service: serve error: %s\n", err.Error())
+		}
+	}()
+
+	return nil
+}
+
+// Stop shuts down the metrics HTTP server.
+func (s *HTTPMetricsService) Stop(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// meterCollector implements prometheus.Collector on top of a meter.Meter. It
+// caches the last successful measurement so a scrape never re-opens the
+// serial port; the actual polling happens on the regular read loop via Get,
+// which wraps meter.Meter.Get and feeds the result to Observe.
+type meterCollector struct {
+	meter     *meter.Meter
+	meterPort string
+
+	readsTotal      prometheus.Counter
+	readErrorsTotal prometheus.Counter
+	readDuration    prometheus.Histogram
+
+	mu   sync.Mutex
+	last *model.Measurement
+}
+
+func newMeterCollector(m *meter.Meter, meterPort string) *meterCollector {
+	return &meterCollector{
+		meter:     m,
+		meterPort: meterPort,
+		readsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "iec62056_reads_total",
+			Help: "Total number of meter read attempts.",
+		}),
+		readErrorsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "iec62056_read_errors_total",
+			Help: "Total number of failed meter reads.",
+		}),
+		readDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "iec62056_read_duration_seconds",
+			Help:    "Duration of a single meter read, in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}),
+	}
+}
+
+// Get runs ctx through the wrapped meter.Meter.Get and records the outcome
+// via Observe before returning it.
+func (c *meterCollector) Get(ctx context.Context) (*model.Measurement, error) {
+	start := time.Now()
+	msm, err := c.meter.Get(ctx)
+	c.Observe(msm, time.Since(start), err)
+	return msm, err
+}
+
+// Observe records the outcome of a meter.Meter.Get call. Get calls this
+// after every read; since meterCollector is unexported, callers outside this
+// package reach it only through HTTPMetricsService.Get.
+func (c *meterCollector) Observe(msm *model.Measurement, dur time.Duration, err error) {
+	c.readsTotal.Inc()
+	c.readDuration.Observe(dur.Seconds())
+	if err != nil {
+		c.readErrorsTotal.Inc()
+		return
+	}
+
+	c.mu.Lock()
+	c.last = msm
+	c.mu.Unlock()
+}
+
+// Describe implements prometheus.Collector.
+func (c *meterCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.readsTotal.Describe(ch)
+	c.readErrorsTotal.Describe(ch)
+	c.readDuration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector. It turns every OBIS entry of the
+// last observed measurement into a gauge, plus the read counters/histogram.
+func (c *meterCollector) Collect(ch chan<- prometheus.Metric) {
+	c.readsTotal.Collect(ch)
+	c.readErrorsTotal.Collect(ch)
+	c.readDuration.Collect(ch)
+
+	c.mu.Lock()
+	msm := c.last
+	c.mu.Unlock()
+	if msm == nil {
+		return
+	}
+
+	for _, reg := range msm.Registers {
+		g := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "iec62056_register",
+			Help: "Last value read for a single OBIS register.",
+			ConstLabels: prometheus.Labels{
+				"obis": reg.OBIS,
+				"unit": reg.Unit,
+				"port": c.meterPort,
+			},
+		})
+		g.Set(reg.Value)
+		g.Collect(ch)
+	}
+}
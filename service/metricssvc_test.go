@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/mariuszjedrzejewski/iec62056/adapters/meter"
+	"github.com/mariuszjedrzejewski/iec62056/iec"
+	"github.com/mariuszjedrzejewski/iec62056/model"
+)
+
+func TestMeterCollectorObserve(t *testing.T) {
+	c := newMeterCollector(&meter.Meter{}, "/dev/ttyUSB0")
+
+	msm := &model.Measurement{
+		Registers: []model.Register{
+			{OBIS: "1.8.0", Value: 123.4, Unit: "kWh"},
+		},
+	}
+	c.Observe(msm, 50*time.Millisecond, nil)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	if got := testutil.ToFloat64(c.readsTotal); got != 1 {
+		t.Errorf("reads total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(c.readErrorsTotal); got != 0 {
+		t.Errorf("read errors total = %v, want 0", got)
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %s", err.Error())
+	}
+	var sawRegister bool
+	for _, mf := range mfs {
+		if mf.GetName() == "iec62056_register" {
+			sawRegister = true
+		}
+	}
+	if !sawRegister {
+		t.Fatal("expected an iec62056_register gauge after Observe, found none")
+	}
+}
+
+func TestMeterCollectorObserveError(t *testing.T) {
+	c := newMeterCollector(&meter.Meter{}, "/dev/ttyUSB0")
+
+	c.Observe(nil, time.Millisecond, context.DeadlineExceeded)
+
+	if got := testutil.ToFloat64(c.readsTotal); got != 1 {
+		t.Errorf("reads total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(c.readErrorsTotal); got != 1 {
+		t.Errorf("read errors total = %v, want 1", got)
+	}
+}
+
+func TestMeterCollectorGetWrapsMeter(t *testing.T) {
+	m := &meter.Meter{
+		PortSettings: iec.NewDefaultSettings(),
+		PortName:     "/dev/does-not-exist-iec62056-test",
+	}
+	c := newMeterCollector(m, "/dev/does-not-exist-iec62056-test")
+
+	_, err := c.Get(context.Background())
+	if err == nil {
+		t.Fatal("expected Get against a nonexistent port to fail")
+	}
+
+	if got := testutil.ToFloat64(c.readsTotal); got != 1 {
+		t.Errorf("reads total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(c.readErrorsTotal); got != 1 {
+		t.Errorf("read errors total = %v, want 1", got)
+	}
+}
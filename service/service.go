@@ -0,0 +1,16 @@
+// Package service hosts the long-running HTTP services (local measurement
+// cache, Prometheus metrics) that sit in front of a model.MeasurementRepo.
+package service
+
+import "context"
+
+// Service is a long-running component with an explicit start/stop
+// lifecycle, started and stopped by the process's main supervisor.
+type Service interface {
+	// Start begins serving and returns once it is safely running, or with
+	// an error if it failed to start (e.g. the listen address is already
+	// in use). It must not block for the lifetime of the service.
+	Start(ctx context.Context) error
+	// Stop gracefully shuts the service down, honouring ctx's deadline.
+	Stop(ctx context.Context) error
+}